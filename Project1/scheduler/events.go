@@ -0,0 +1,202 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// EventKind names one kind of scheduling decision recorded by an EventSink.
+type EventKind string
+
+const (
+	// EventArrival marks a process becoming known to the scheduler at its
+	// ArrivalTime, carrying the Burst it needs to complete.
+	EventArrival EventKind = "arrival"
+	// EventDispatch marks a process starting (or resuming) execution.
+	EventDispatch EventKind = "dispatch"
+	// EventPreempt marks a process being switched out before it finishes,
+	// whether by a quantum expiring or a higher-priority process arriving.
+	EventPreempt EventKind = "preempt"
+	// EventComplete marks a process finishing its last unit of burst.
+	EventComplete EventKind = "complete"
+	// EventQueueMove marks a process changing MLFQ level, by demotion or by
+	// a starvation-prevention boost. Only MLFQSchedule emits it.
+	EventQueueMove EventKind = "queue_move"
+)
+
+// Event is one typed scheduling decision. Not every field applies to every
+// Kind: Burst is set only on EventArrival, and Queue only on the events an
+// MLFQ run emits.
+type Event struct {
+	Seq   int64     `json:"seq"`
+	Kind  EventKind `json:"kind"`
+	PID   int64     `json:"pid"`
+	Time  int64     `json:"time"`
+	Burst int64     `json:"burst,omitempty"`
+	Queue *int      `json:"queue,omitempty"`
+}
+
+// EventSink receives the events a scheduler emits as it runs.
+type EventSink interface {
+	Emit(Event) error
+}
+
+// NopSink discards every event. It's the default for callers that don't
+// want a replay log.
+type NopSink struct{}
+
+func (NopSink) Emit(Event) error { return nil }
+
+// JSONLSink writes one JSON object per line, in the order events are
+// emitted, stamping each with a logical sequence number rather than a
+// wall-clock timestamp so that recording the same run twice produces a
+// byte-identical log.
+type JSONLSink struct {
+	w   io.Writer
+	seq int64
+}
+
+// NewJSONLSink returns a JSONLSink writing to w.
+func NewJSONLSink(w io.Writer) *JSONLSink {
+	return &JSONLSink{w: w}
+}
+
+func (s *JSONLSink) Emit(e Event) error {
+	e.Seq = s.seq
+	s.seq++
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("%w: marshaling event", err)
+	}
+	_, err = fmt.Fprintln(s.w, string(data))
+	return err
+}
+
+// Metrics is the aggregate, per-run statistics ReplayFromLog reconstructs
+// from a log, mirroring the fields ScheduleResult carries alongside its
+// Gantt trace.
+type Metrics struct {
+	AverageWait       float64
+	AverageTurnaround float64
+	Throughput        float64
+	ContextSwitches   int
+	AverageResponse   *float64
+}
+
+// replayProcess accumulates one process's events while replaying a log.
+type replayProcess struct {
+	arrival, burst int64
+	dispatchStart  int64
+	firstDispatch  int64
+	dispatched     bool
+	finish         int64
+	finished       bool
+}
+
+// ReplayFromLog reconstructs the Gantt trace and summary Metrics of a past
+// run from the JSONL log an EventSink recorded, without re-running the
+// scheduling algorithm.
+func ReplayFromLog(r io.Reader) ([]TimeSlice, Metrics, error) {
+	states := make(map[int64]*replayProcess)
+	var order []int64
+	var gantt []TimeSlice
+
+	dec := json.NewDecoder(r)
+	for dec.More() {
+		var e Event
+		if err := dec.Decode(&e); err != nil {
+			return nil, Metrics{}, fmt.Errorf("%w: decoding replay event", err)
+		}
+
+		st, ok := states[e.PID]
+		if !ok {
+			st = &replayProcess{}
+			states[e.PID] = st
+			order = append(order, e.PID)
+		}
+
+		switch e.Kind {
+		case EventArrival:
+			st.arrival = e.Time
+			st.burst = e.Burst
+		case EventDispatch:
+			st.dispatchStart = e.Time
+			if !st.dispatched {
+				st.firstDispatch = e.Time
+				st.dispatched = true
+			}
+		case EventPreempt:
+			gantt = append(gantt, TimeSlice{PID: e.PID, Start: st.dispatchStart, Stop: e.Time})
+		case EventComplete:
+			gantt = append(gantt, TimeSlice{PID: e.PID, Start: st.dispatchStart, Stop: e.Time})
+			st.finish = e.Time
+			st.finished = true
+		case EventQueueMove:
+			// Recorded for diagnostics only; it changes neither the Gantt
+			// trace nor the metrics below.
+		}
+	}
+
+	var totalWait, totalTurnaround, totalResponse float64
+	var lastCompletion int64
+	completed := 0
+	for _, pid := range order {
+		st := states[pid]
+		if !st.finished {
+			continue
+		}
+
+		turnaround := st.finish - st.arrival
+		wait := turnaround - st.burst
+		totalWait += float64(wait)
+		totalTurnaround += float64(turnaround)
+		totalResponse += float64(st.firstDispatch - st.arrival)
+		if st.finish > lastCompletion {
+			lastCompletion = st.finish
+		}
+		completed++
+	}
+
+	if completed == 0 {
+		return gantt, Metrics{}, nil
+	}
+
+	aveResponse := totalResponse / float64(completed)
+	return gantt, Metrics{
+		AverageWait:       totalWait / float64(completed),
+		AverageTurnaround: totalTurnaround / float64(completed),
+		Throughput:        float64(completed) / float64(lastCompletion),
+		ContextSwitches:   contextSwitches(gantt),
+		AverageResponse:   &aveResponse,
+	}, nil
+}
+
+// emitArrivals records every process's EventArrival, in the order given.
+func emitArrivals(sink EventSink, processes []Process) {
+	for _, p := range processes {
+		_ = sink.Emit(Event{Kind: EventArrival, PID: p.ProcessID, Time: p.ArrivalTime, Burst: p.BurstDuration})
+	}
+}
+
+// emitTransitions records the EventDispatch that starts each contiguous run
+// in gantt, followed by EventComplete if it ends at that process's recorded
+// finish time or EventPreempt otherwise. It's shared by every scheduler
+// except MLFQSchedule, which also needs to tag dispatches with a queue
+// level and emit EventQueueMove.
+func emitTransitions(sink EventSink, gantt []TimeSlice, finish map[int64]int64) {
+	for _, ts := range mergeTimeSlices(gantt) {
+		_ = sink.Emit(Event{Kind: EventDispatch, PID: ts.PID, Time: ts.Start})
+		if ts.Stop == finish[ts.PID] {
+			_ = sink.Emit(Event{Kind: EventComplete, PID: ts.PID, Time: ts.Stop})
+		} else {
+			_ = sink.Emit(Event{Kind: EventPreempt, PID: ts.PID, Time: ts.Stop})
+		}
+	}
+}
+
+// intRef returns a pointer to a copy of v, for populating Event.Queue.
+func intRef(v int) *int {
+	return &v
+}
@@ -0,0 +1,288 @@
+package scheduler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+)
+
+// scheduleColumns names the table columns shared by every scheduler's rows.
+var scheduleColumns = []string{"ID", "Priority", "Burst", "Arrival", "Wait", "Turnaround", "Exit"}
+
+// ScheduleResult is the format-independent output of a single scheduler run,
+// consumed by a Renderer.
+type ScheduleResult struct {
+	Title             string
+	Gantt             []TimeSlice
+	QueueBands        [][]TimeSlice // optional: set only by MLFQSchedule
+	Rows              [][]string    // one cell per scheduleColumns; input order, or completion order for SJF, SJF-priority and RR
+	AverageWait       float64
+	AverageTurnaround float64
+	Throughput        float64
+	ContextSwitches   int
+	AverageResponse   *float64 // optional: set by MLFQSchedule and by replays of a recorded log
+}
+
+// Renderer turns a scheduler's ScheduleResult into a concrete output format.
+// Render is called once per scheduling run; Close finalizes the output
+// (e.g. closing off an HTML document or flushing a buffered JSON array) once
+// every run has been rendered. Renderers with nothing to finalize no-op on
+// Close.
+type Renderer interface {
+	Render(w io.Writer, result ScheduleResult) error
+	Close(w io.Writer) error
+}
+
+// NewRenderer builds the Renderer named by output ("text", "html", "json",
+// or "csv").
+func NewRenderer(output string) (Renderer, error) {
+	switch output {
+	case "", "text":
+		return TextRenderer{}, nil
+	case "html":
+		return &HTMLRenderer{}, nil
+	case "json":
+		return &JSONRenderer{}, nil
+	case "csv":
+		return &CSVRenderer{}, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown --output %q", ErrInvalidArgs, output)
+	}
+}
+
+// TextRenderer reproduces the schedulers' original text/table output.
+type TextRenderer struct{}
+
+func (TextRenderer) Render(w io.Writer, result ScheduleResult) error {
+	outputTitle(w, result.Title)
+	for level, band := range result.QueueBands {
+		_, _ = fmt.Fprintf(w, "Queue %d\n", level)
+		outputGantt(w, band)
+	}
+	outputGantt(w, result.Gantt)
+	outputSchedule(w, result.Rows, result.AverageWait, result.AverageTurnaround, result.Throughput, result.ContextSwitches)
+	if result.AverageResponse != nil {
+		_, _ = fmt.Fprintf(w, "Average response time: %.2f\n\n", *result.AverageResponse)
+	}
+	return nil
+}
+
+func (TextRenderer) Close(io.Writer) error { return nil }
+
+// CSVRenderer accumulates every scheduler's rows, tagged by algorithm, into
+// one CSV artifact written on Close.
+type CSVRenderer struct {
+	results []ScheduleResult
+}
+
+func (r *CSVRenderer) Render(_ io.Writer, result ScheduleResult) error {
+	r.results = append(r.results, result)
+	return nil
+}
+
+func (r *CSVRenderer) Close(w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write(append([]string{"Algorithm"}, scheduleColumns...)); err != nil {
+		return err
+	}
+	for _, result := range r.results {
+		for _, row := range result.Rows {
+			if err := cw.Write(append([]string{result.Title}, row...)); err != nil {
+				return err
+			}
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// jsonScheduleResult is the JSON-friendly projection of a ScheduleResult.
+type jsonScheduleResult struct {
+	Algorithm         string           `json:"algorithm"`
+	Gantt             []TimeSlice      `json:"gantt"`
+	Processes         []jsonProcessRow `json:"processes"`
+	AverageWait       float64          `json:"averageWait"`
+	AverageTurnaround float64          `json:"averageTurnaround"`
+	Throughput        float64          `json:"throughput"`
+	ContextSwitches   int              `json:"contextSwitches"`
+	AverageResponse   *float64         `json:"averageResponse,omitempty"`
+}
+
+type jsonProcessRow struct {
+	ID         string `json:"id"`
+	Priority   string `json:"priority"`
+	Burst      string `json:"burst"`
+	Arrival    string `json:"arrival"`
+	Wait       string `json:"wait"`
+	Turnaround string `json:"turnaround"`
+	Exit       string `json:"exit"`
+}
+
+// JSONRenderer accumulates every scheduler's result into one JSON array
+// written on Close.
+type JSONRenderer struct {
+	results []jsonScheduleResult
+}
+
+func (r *JSONRenderer) Render(_ io.Writer, result ScheduleResult) error {
+	rows := make([]jsonProcessRow, len(result.Rows))
+	for i, row := range result.Rows {
+		rows[i] = jsonProcessRow{
+			ID: row[0], Priority: row[1], Burst: row[2], Arrival: row[3],
+			Wait: row[4], Turnaround: row[5], Exit: row[6],
+		}
+	}
+
+	r.results = append(r.results, jsonScheduleResult{
+		Algorithm:         result.Title,
+		Gantt:             result.Gantt,
+		Processes:         rows,
+		AverageWait:       result.AverageWait,
+		AverageTurnaround: result.AverageTurnaround,
+		Throughput:        result.Throughput,
+		ContextSwitches:   result.ContextSwitches,
+		AverageResponse:   result.AverageResponse,
+	})
+	return nil
+}
+
+func (r *JSONRenderer) Close(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r.results)
+}
+
+// HTMLRenderer accumulates every scheduler's result and, on Close, writes a
+// single self-contained HTML file (inline CSS and JS only, no external
+// references) with one section per algorithm: an SVG Gantt chart with hover
+// tooltips and a sortable schedule table.
+type HTMLRenderer struct {
+	results []ScheduleResult
+}
+
+func (r *HTMLRenderer) Render(_ io.Writer, result ScheduleResult) error {
+	r.results = append(r.results, result)
+	return nil
+}
+
+func (r *HTMLRenderer) Close(w io.Writer) error {
+	if _, err := fmt.Fprint(w, htmlHeader); err != nil {
+		return err
+	}
+	for i, result := range r.results {
+		writeHTMLSection(w, i, result)
+	}
+	_, err := fmt.Fprint(w, htmlFooter)
+	return err
+}
+
+func writeHTMLSection(w io.Writer, index int, result ScheduleResult) {
+	waitAndTurnaround := make(map[string][2]string, len(result.Rows))
+	for _, row := range result.Rows {
+		waitAndTurnaround[row[0]] = [2]string{row[4], row[5]}
+	}
+
+	const pxPerUnit = 24
+	const barHeight = 28
+
+	merged := mergeTimeSlices(result.Gantt)
+	width := pxPerUnit
+	for _, ts := range merged {
+		if w := int(ts.Stop) * pxPerUnit; w > width {
+			width = w
+		}
+	}
+
+	_, _ = fmt.Fprintf(w, "<section class=\"run\">\n<h2>%s</h2>\n", html.EscapeString(result.Title))
+
+	_, _ = fmt.Fprintf(w, "<svg class=\"gantt\" viewBox=\"0 0 %d %d\" width=\"%d\" height=\"%d\">\n", width, barHeight, width, barHeight)
+	for _, ts := range merged {
+		pid := fmt.Sprint(ts.PID)
+		var wait, turnaround string
+		if info, ok := waitAndTurnaround[pid]; ok {
+			wait, turnaround = info[0], info[1]
+		}
+		_, _ = fmt.Fprintf(w,
+			"<rect x=\"%d\" y=\"0\" width=\"%d\" height=\"%d\" fill=\"%s\"><title>PID %s: start %d, stop %d, wait %s, turnaround %s</title></rect>\n",
+			int(ts.Start)*pxPerUnit, int(ts.Stop-ts.Start)*pxPerUnit, barHeight, pidColor(ts.PID), pid, ts.Start, ts.Stop, wait, turnaround)
+	}
+	_, _ = fmt.Fprint(w, "</svg>\n")
+
+	_, _ = fmt.Fprintf(w, "<table class=\"schedule\" id=\"run-%d\">\n<thead><tr>", index)
+	for _, col := range scheduleColumns {
+		_, _ = fmt.Fprintf(w, "<th>%s</th>", html.EscapeString(col))
+	}
+	_, _ = fmt.Fprint(w, "</tr></thead>\n<tbody>\n")
+	for _, row := range result.Rows {
+		_, _ = fmt.Fprint(w, "<tr>")
+		for _, cell := range row {
+			_, _ = fmt.Fprintf(w, "<td>%s</td>", html.EscapeString(cell))
+		}
+		_, _ = fmt.Fprint(w, "</tr>\n")
+	}
+	_, _ = fmt.Fprint(w, "</tbody>\n</table>\n")
+
+	_, _ = fmt.Fprintf(w, "<p class=\"summary\">Average wait %.2f &middot; average turnaround %.2f &middot; throughput %.2f/t &middot; %d context switches",
+		result.AverageWait, result.AverageTurnaround, result.Throughput, result.ContextSwitches)
+	if result.AverageResponse != nil {
+		_, _ = fmt.Fprintf(w, " &middot; average response %.2f", *result.AverageResponse)
+	}
+	_, _ = fmt.Fprint(w, "</p>\n</section>\n")
+}
+
+// pidColor deterministically maps a PID to a distinct, readable color so the
+// same process keeps the same color across every algorithm's Gantt chart.
+func pidColor(pid int64) string {
+	hue := (pid*47 + 360) % 360
+	return fmt.Sprintf("hsl(%d, 65%%, 55%%)", hue)
+}
+
+const htmlHeader = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Scheduling report</title>
+<style>
+body { font-family: sans-serif; margin: 2rem; color: #1a1a1a; }
+section.run { margin-bottom: 3rem; }
+svg.gantt { display: block; margin: 0.5rem 0; }
+svg.gantt rect { stroke: #fff; stroke-width: 1; }
+table.schedule { border-collapse: collapse; }
+table.schedule th, table.schedule td { border: 1px solid #ccc; padding: 0.25rem 0.6rem; text-align: right; }
+table.schedule th { cursor: pointer; background: #f0f0f0; }
+p.summary { color: #444; }
+</style>
+<script>
+document.addEventListener('DOMContentLoaded', function () {
+  document.querySelectorAll('table.schedule').forEach(function (table) {
+    table.querySelectorAll('th').forEach(function (th, col) {
+      th.addEventListener('click', function () {
+        var tbody = table.querySelector('tbody');
+        var rows = Array.prototype.slice.call(tbody.querySelectorAll('tr'));
+        var asc = th.dataset.asc !== 'true';
+        rows.sort(function (a, b) {
+          var av = a.children[col].textContent.trim();
+          var bv = b.children[col].textContent.trim();
+          var an = parseFloat(av), bn = parseFloat(bv);
+          var cmp = (!isNaN(an) && !isNaN(bn)) ? an - bn : av.localeCompare(bv);
+          return asc ? cmp : -cmp;
+        });
+        th.dataset.asc = asc;
+        rows.forEach(function (row) { tbody.appendChild(row); });
+      });
+    });
+  });
+});
+</script>
+</head>
+<body>
+<h1>Scheduling report</h1>
+`
+
+const htmlFooter = `</body>
+</html>
+`
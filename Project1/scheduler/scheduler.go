@@ -0,0 +1,985 @@
+// Package scheduler implements CPU scheduling simulators (FCFS, SJF,
+// round-robin, preemptive SJF/priority, and multilevel feedback queue) plus
+// the process loaders and renderers they share.
+package scheduler
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+type (
+	Process struct {
+		ProcessID     int64
+		ArrivalTime   int64
+		BurstDuration int64
+		Priority      int64
+	}
+	TimeSlice struct {
+		PID   int64
+		Start int64
+		Stop  int64
+	}
+)
+
+// TieBreak selects how a preemptive scheduler resolves two ready processes
+// that tie on its primary ordering key (remaining time or priority).
+type TieBreak int
+
+const (
+	// TieBreakFIFO prefers whichever process arrived first.
+	TieBreakFIFO TieBreak = iota
+	// TieBreakPriority prefers the lower Priority value.
+	TieBreakPriority
+	// TieBreakPID prefers the lower ProcessID.
+	TieBreakPID
+)
+
+// SchedulerOptions configures the tie-breaking behavior of the preemptive
+// schedulers.
+type SchedulerOptions struct {
+	TieBreak TieBreak
+}
+
+//region Schedulers
+
+// FCFSSchedule outputs a schedule of processes in a GANTT chart and a table of timing given:
+// • an output writer
+// • a title for the chart
+// • a slice of processes
+// • the renderer to emit the result through
+// • a sink to record the run's scheduling decisions to (NopSink{} to discard)
+func FCFSSchedule(w io.Writer, title string, processes []Process, renderer Renderer, sink EventSink) {
+	var (
+		serviceTime     int64
+		totalWait       float64
+		totalTurnaround float64
+		lastCompletion  float64
+		waitingTime     int64
+		schedule        = make([][]string, len(processes))
+		gantt           = make([]TimeSlice, 0)
+		finish          = make(map[int64]int64, len(processes))
+	)
+	emitArrivals(sink, processes)
+	for i := range processes {
+		if processes[i].ArrivalTime > 0 {
+			waitingTime = serviceTime - processes[i].ArrivalTime
+		}
+		totalWait += float64(waitingTime)
+
+		start := waitingTime + processes[i].ArrivalTime
+
+		turnaround := processes[i].BurstDuration + waitingTime
+		totalTurnaround += float64(turnaround)
+
+		completion := processes[i].BurstDuration + processes[i].ArrivalTime + waitingTime
+		lastCompletion = float64(completion)
+		finish[processes[i].ProcessID] = completion
+
+		schedule[i] = []string{
+			fmt.Sprint(processes[i].ProcessID),
+			fmt.Sprint(processes[i].Priority),
+			fmt.Sprint(processes[i].BurstDuration),
+			fmt.Sprint(processes[i].ArrivalTime),
+			fmt.Sprint(waitingTime),
+			fmt.Sprint(turnaround),
+			fmt.Sprint(completion),
+		}
+		serviceTime += processes[i].BurstDuration
+
+		gantt = append(gantt, TimeSlice{
+			PID:   processes[i].ProcessID,
+			Start: start,
+			Stop:  serviceTime,
+		})
+	}
+
+	count := float64(len(processes))
+	aveWait := totalWait / count
+	aveTurnaround := totalTurnaround / count
+	aveThroughput := count / lastCompletion
+
+	emitTransitions(sink, gantt, finish)
+
+	_ = renderer.Render(w, ScheduleResult{
+		Title:             title,
+		Gantt:             gantt,
+		Rows:              schedule,
+		AverageWait:       aveWait,
+		AverageTurnaround: aveTurnaround,
+		Throughput:        aveThroughput,
+		ContextSwitches:   contextSwitches(gantt),
+	})
+}
+
+// SRTFSchedule outputs a schedule for Shortest-Remaining-Time-First (preemptive
+// SJF) scheduling given:
+// • an output writer
+// • a title for the chart
+// • a slice of processes
+// • options controlling how ties on remaining time are broken
+// • the renderer to emit the result through
+// • a sink to record the run's scheduling decisions to (NopSink{} to discard)
+//
+// It simulates one time unit at a time, re-evaluating the ready set on every
+// arrival and every completion, and records a distinct TimeSlice per unit so
+// that every preemption shows up in the replay log; outputGantt merges
+// adjacent same-PID slices for display.
+func SRTFSchedule(w io.Writer, title string, processes []Process, opts SchedulerOptions, renderer Renderer, sink EventSink) {
+	remaining := make([]Process, len(processes))
+	copy(remaining, processes)
+
+	burstLeft := make([]int64, len(remaining))
+	finish := make([]int64, len(remaining))
+	for i := range remaining {
+		burstLeft[i] = remaining[i].BurstDuration
+	}
+
+	emitArrivals(sink, remaining)
+
+	var (
+		currTime  int64
+		completed int
+		gantt     = make([]TimeSlice, 0)
+	)
+
+	for completed < len(remaining) {
+		idx := selectByRemaining(remaining, burstLeft, currTime, opts.TieBreak)
+		if idx == -1 {
+			currTime++
+			continue
+		}
+
+		gantt = append(gantt, TimeSlice{PID: remaining[idx].ProcessID, Start: currTime, Stop: currTime + 1})
+		burstLeft[idx]--
+		currTime++
+
+		if burstLeft[idx] == 0 {
+			finish[idx] = currTime
+			completed++
+		}
+	}
+
+	schedule, aveWait, aveTurnaround := buildPreemptiveSchedule(remaining, finish)
+	aveThroughput := float64(len(remaining)) / float64(currTime)
+
+	emitTransitions(sink, gantt, finishByPID(remaining, finish))
+
+	_ = renderer.Render(w, ScheduleResult{
+		Title:             title,
+		Gantt:             gantt,
+		Rows:              schedule,
+		AverageWait:       aveWait,
+		AverageTurnaround: aveTurnaround,
+		Throughput:        aveThroughput,
+		ContextSwitches:   contextSwitches(gantt),
+	})
+}
+
+// PreemptivePrioritySchedule outputs a schedule for preemptive priority
+// scheduling (lower Priority value runs first) given:
+// • an output writer
+// • a title for the chart
+// • a slice of processes
+// • options controlling how ties on priority are broken
+// • the renderer to emit the result through
+// • a sink to record the run's scheduling decisions to (NopSink{} to discard)
+//
+// It shares its tick-by-tick simulation shape with SRTFSchedule, swapping
+// the ready-process selection key from remaining time to priority.
+func PreemptivePrioritySchedule(w io.Writer, title string, processes []Process, opts SchedulerOptions, renderer Renderer, sink EventSink) {
+	remaining := make([]Process, len(processes))
+	copy(remaining, processes)
+
+	burstLeft := make([]int64, len(remaining))
+	finish := make([]int64, len(remaining))
+	for i := range remaining {
+		burstLeft[i] = remaining[i].BurstDuration
+	}
+
+	emitArrivals(sink, remaining)
+
+	var (
+		currTime  int64
+		completed int
+		gantt     = make([]TimeSlice, 0)
+	)
+
+	for completed < len(remaining) {
+		idx := selectByPriority(remaining, burstLeft, currTime, opts.TieBreak)
+		if idx == -1 {
+			currTime++
+			continue
+		}
+
+		gantt = append(gantt, TimeSlice{PID: remaining[idx].ProcessID, Start: currTime, Stop: currTime + 1})
+		burstLeft[idx]--
+		currTime++
+
+		if burstLeft[idx] == 0 {
+			finish[idx] = currTime
+			completed++
+		}
+	}
+
+	schedule, aveWait, aveTurnaround := buildPreemptiveSchedule(remaining, finish)
+	aveThroughput := float64(len(remaining)) / float64(currTime)
+
+	emitTransitions(sink, gantt, finishByPID(remaining, finish))
+
+	_ = renderer.Render(w, ScheduleResult{
+		Title:             title,
+		Gantt:             gantt,
+		Rows:              schedule,
+		AverageWait:       aveWait,
+		AverageTurnaround: aveTurnaround,
+		Throughput:        aveThroughput,
+		ContextSwitches:   contextSwitches(gantt),
+	})
+}
+
+// selectByRemaining returns the index of the ready process (arrived, not yet
+// finished) with the least remaining burst time, breaking ties per tie.
+// It returns -1 if no unfinished process has arrived yet.
+func selectByRemaining(processes []Process, burstLeft []int64, currTime int64, tie TieBreak) int {
+	best := -1
+	for i := range processes {
+		if processes[i].ArrivalTime > currTime || burstLeft[i] == 0 {
+			continue
+		}
+		if best == -1 || burstLeft[i] < burstLeft[best] ||
+			(burstLeft[i] == burstLeft[best] && prefers(processes, i, best, tie)) {
+			best = i
+		}
+	}
+	return best
+}
+
+// selectByPriority returns the index of the ready process with the lowest
+// Priority value, breaking ties per tie. It returns -1 if no unfinished
+// process has arrived yet.
+func selectByPriority(processes []Process, burstLeft []int64, currTime int64, tie TieBreak) int {
+	best := -1
+	for i := range processes {
+		if processes[i].ArrivalTime > currTime || burstLeft[i] == 0 {
+			continue
+		}
+		if best == -1 || processes[i].Priority < processes[best].Priority ||
+			(processes[i].Priority == processes[best].Priority && prefers(processes, i, best, tie)) {
+			best = i
+		}
+	}
+	return best
+}
+
+// prefers reports whether process i should be chosen over process j once
+// their primary scheduling key is tied.
+func prefers(processes []Process, i, j int, tie TieBreak) bool {
+	switch tie {
+	case TieBreakPriority:
+		return processes[i].Priority < processes[j].Priority
+	case TieBreakPID:
+		return processes[i].ProcessID < processes[j].ProcessID
+	default: // TieBreakFIFO
+		return processes[i].ArrivalTime < processes[j].ArrivalTime
+	}
+}
+
+// buildPreemptiveSchedule turns per-process finish times into the schedule
+// table rows plus the average wait and turnaround, shared by both preemptive
+// schedulers.
+func buildPreemptiveSchedule(processes []Process, finish []int64) ([][]string, float64, float64) {
+	schedule := make([][]string, len(processes))
+	var totalWait, totalTurnaround float64
+
+	for i := range processes {
+		turnaround := finish[i] - processes[i].ArrivalTime
+		wait := turnaround - processes[i].BurstDuration
+		totalWait += float64(wait)
+		totalTurnaround += float64(turnaround)
+
+		schedule[i] = []string{
+			fmt.Sprint(processes[i].ProcessID),
+			fmt.Sprint(processes[i].Priority),
+			fmt.Sprint(processes[i].BurstDuration),
+			fmt.Sprint(processes[i].ArrivalTime),
+			fmt.Sprint(wait),
+			fmt.Sprint(turnaround),
+			fmt.Sprint(finish[i]),
+		}
+	}
+
+	count := float64(len(processes))
+	return schedule, totalWait / count, totalTurnaround / count
+}
+
+// finishByPID re-keys a preemptive scheduler's index-aligned finish times by
+// ProcessID, for emitTransitions.
+func finishByPID(processes []Process, finish []int64) map[int64]int64 {
+	byPID := make(map[int64]int64, len(processes))
+	for i := range processes {
+		byPID[processes[i].ProcessID] = finish[i]
+	}
+	return byPID
+}
+
+func SJFPrioritySchedule(w io.Writer, title string, processes []Process, renderer Renderer, sink EventSink) {
+	sort.Slice(processes, func(i, j int) bool {
+		if processes[i].ArrivalTime != processes[j].ArrivalTime {
+			return processes[i].ArrivalTime < processes[j].ArrivalTime
+		}
+		if processes[i].Priority != processes[j].Priority {
+			return processes[i].Priority < processes[j].Priority
+		}
+		return processes[i].BurstDuration < processes[j].BurstDuration
+	})
+
+	var (
+		currTime int64
+		waitTime float64
+		turn     float64
+		gantt    = make([]TimeSlice, 0)
+		schedule = make([][]string, 0, len(processes))
+		finish   = make(map[int64]int64, len(processes))
+	)
+
+	emitArrivals(sink, processes)
+
+	remainingProcesses := make([]Process, len(processes))
+	copy(remainingProcesses, processes)
+
+	for len(remainingProcesses) > 0 {
+		var shortest *Process
+		for i := range remainingProcesses {
+			if remainingProcesses[i].ArrivalTime <= currTime {
+				if shortest == nil || remainingProcesses[i].BurstDuration < shortest.BurstDuration || (remainingProcesses[i].BurstDuration == shortest.BurstDuration && remainingProcesses[i].Priority < shortest.Priority) {
+					shortest = &remainingProcesses[i]
+				}
+			}
+		}
+
+		if shortest == nil {
+			nextArrivalTime := remainingProcesses[0].ArrivalTime
+			for _, process := range remainingProcesses {
+				if process.ArrivalTime > currTime && process.ArrivalTime < nextArrivalTime {
+					nextArrivalTime = process.ArrivalTime
+				}
+			}
+			currTime = nextArrivalTime
+		} else {
+			gantt = append(gantt, TimeSlice{
+				PID:   shortest.ProcessID,
+				Start: currTime,
+				Stop:  currTime + shortest.BurstDuration,
+			})
+
+			waitTime += float64(currTime - shortest.ArrivalTime)
+			turn += float64(currTime+shortest.BurstDuration-shortest.ArrivalTime) - waitTime
+
+			schedule = append(schedule, []string{
+				strconv.FormatInt(shortest.ProcessID, 10),
+				strconv.FormatInt(shortest.Priority, 10),
+				strconv.FormatInt(shortest.BurstDuration, 10),
+				strconv.FormatInt(shortest.ArrivalTime, 10),
+				strconv.FormatFloat(float64(currTime-shortest.ArrivalTime), 'f', 2, 64),
+				strconv.FormatFloat(float64(currTime+shortest.BurstDuration-shortest.ArrivalTime), 'f', 2, 64),
+				strconv.FormatInt(currTime+shortest.BurstDuration, 10),
+			})
+			finish[shortest.ProcessID] = currTime + shortest.BurstDuration
+
+			for i, process := range remainingProcesses {
+				if process.ProcessID == shortest.ProcessID {
+					remainingProcesses = append(remainingProcesses[:i], remainingProcesses[i+1:]...)
+					break
+				}
+			}
+
+			currTime += shortest.BurstDuration
+		}
+	}
+
+	throughput := float64(len(processes)) / float64(currTime)
+
+	emitTransitions(sink, gantt, finish)
+
+	_ = renderer.Render(w, ScheduleResult{
+		Title:             title,
+		Gantt:             gantt,
+		Rows:              schedule,
+		AverageWait:       waitTime / float64(len(processes)),
+		AverageTurnaround: turn / float64(len(processes)),
+		Throughput:        throughput,
+		ContextSwitches:   contextSwitches(gantt),
+	})
+}
+
+func SJFSchedule(w io.Writer, title string, processes []Process, renderer Renderer, sink EventSink) {
+	sort.Slice(processes, func(i, j int) bool {
+		return processes[i].ArrivalTime < processes[j].ArrivalTime
+	})
+
+	var (
+		currTime int64
+		waitTime float64
+		turn     float64
+		gantt    = make([]TimeSlice, 0)
+		schedule = make([][]string, 0, len(processes))
+		finish   = make(map[int64]int64, len(processes))
+	)
+
+	emitArrivals(sink, processes)
+
+	remainingProcesses := make([]Process, len(processes))
+	copy(remainingProcesses, processes)
+
+	for len(remainingProcesses) > 0 {
+		shortestIndex := 0
+		for i := range remainingProcesses {
+			if remainingProcesses[i].BurstDuration < remainingProcesses[shortestIndex].BurstDuration {
+				shortestIndex = i
+			}
+		}
+		shortest := &remainingProcesses[shortestIndex]
+
+		if shortest.ArrivalTime > currTime {
+			currTime = shortest.ArrivalTime
+		}
+
+		gantt = append(gantt, TimeSlice{
+			PID:   shortest.ProcessID,
+			Start: currTime,
+			Stop:  currTime + shortest.BurstDuration,
+		})
+
+		waitTime += float64(currTime - shortest.ArrivalTime)
+		turn += float64(currTime+shortest.BurstDuration-shortest.ArrivalTime) - waitTime
+
+		schedule = append(schedule, []string{
+			strconv.FormatInt(shortest.ProcessID, 10),
+			strconv.FormatInt(shortest.Priority, 10),
+			strconv.FormatInt(shortest.BurstDuration, 10),
+			strconv.FormatInt(shortest.ArrivalTime, 10),
+			strconv.FormatFloat(float64(currTime-shortest.ArrivalTime), 'f', 2, 64),
+			strconv.FormatFloat(float64(currTime+shortest.BurstDuration-shortest.ArrivalTime), 'f', 2, 64),
+			strconv.FormatInt(currTime+shortest.BurstDuration, 10),
+		})
+		finish[shortest.ProcessID] = currTime + shortest.BurstDuration
+
+		remainingProcesses = append(remainingProcesses[:shortestIndex], remainingProcesses[shortestIndex+1:]...)
+
+		currTime += shortest.BurstDuration
+	}
+
+	throughput := float64(len(processes)) / float64(currTime)
+
+	emitTransitions(sink, gantt, finish)
+
+	_ = renderer.Render(w, ScheduleResult{
+		Title:             title,
+		Gantt:             gantt,
+		Rows:              schedule,
+		AverageWait:       waitTime / float64(len(processes)),
+		AverageTurnaround: turn / float64(len(processes)),
+		Throughput:        throughput,
+		ContextSwitches:   contextSwitches(gantt),
+	})
+}
+
+// quantum time
+func RRSchedule(w io.Writer, title string, processes []Process, quantum int64, renderer Renderer, sink EventSink) {
+	sort.Slice(processes, func(i, j int) bool {
+		return processes[i].ArrivalTime < processes[j].ArrivalTime
+	})
+
+	var (
+		gantt    = make([]TimeSlice, 0)
+		schedule = make([][]string, 0, len(processes))
+		wait     float64
+		turn     float64
+		elapsed  int64
+		finish   = make(map[int64]int64, len(processes))
+	)
+
+	emitArrivals(sink, processes)
+
+	queue := make([]Process, len(processes))
+	copy(queue, processes)
+
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+
+		gantt = append(gantt, TimeSlice{p.ProcessID, elapsed, elapsed + min(p.BurstDuration, quantum)})
+		elapsed += min(p.BurstDuration, quantum)
+
+		p.BurstDuration -= min(p.BurstDuration, quantum)
+
+		if p.BurstDuration > 0 {
+			queue = append(queue, p)
+		} else {
+			w := float64(elapsed - p.ArrivalTime - priorityPenalty(p))
+			t := float64(elapsed - p.ArrivalTime)
+			wait += w
+			turn += t
+
+			schedule = append(schedule, []string{
+				strconv.FormatInt(p.ProcessID, 10),
+				strconv.FormatInt(p.Priority, 10),
+				strconv.FormatInt(p.BurstDuration, 10),
+				strconv.FormatInt(p.ArrivalTime, 10),
+				fmt.Sprintf("%.2f", w),
+				fmt.Sprintf("%.2f", t),
+				strconv.FormatInt(elapsed, 10),
+			})
+			finish[p.ProcessID] = elapsed
+		}
+	}
+
+	tp := float64(len(processes)) / float64(elapsed)
+
+	emitTransitions(sink, gantt, finish)
+
+	_ = renderer.Render(w, ScheduleResult{
+		Title:             title,
+		Gantt:             gantt,
+		Rows:              schedule,
+		AverageWait:       wait / float64(len(processes)),
+		AverageTurnaround: turn / float64(len(processes)),
+		Throughput:        tp,
+		ContextSwitches:   contextSwitches(gantt),
+	})
+}
+
+// QueueDiscipline is the dispatch discipline of a single MLFQ level.
+type QueueDiscipline int
+
+const (
+	// QueueRR preempts the running process once it exhausts the level's quantum.
+	QueueRR QueueDiscipline = iota
+	// QueueFCFS runs the dispatched process to completion, ignoring the quantum.
+	QueueFCFS
+)
+
+// MLFQLevel describes one queue of a multilevel feedback queue scheduler.
+type MLFQLevel struct {
+	Quantum    int64
+	Discipline QueueDiscipline
+}
+
+// MLFQConfig configures MLFQSchedule: the queues from highest to lowest
+// priority, and the interval S at which all processes are boosted back to
+// the top queue to prevent starvation.
+type MLFQConfig struct {
+	Queues        []MLFQLevel
+	BoostInterval int64
+}
+
+// mlfqProcess tracks the per-process state MLFQSchedule needs beyond the
+// static Process record.
+type mlfqProcess struct {
+	proc          Process
+	remaining     int64
+	firstDispatch int64
+	finish        int64
+}
+
+// MLFQSchedule outputs a schedule for a multilevel feedback queue given:
+// • an output writer
+// • a title for the chart
+// • a slice of processes
+// • a MLFQConfig describing the queues and the starvation-prevention boost
+// • the renderer to emit the result through
+// • a sink to record the run's scheduling decisions to (NopSink{} to discard),
+//   including the EventQueueMove transitions unique to this scheduler
+//
+// New arrivals enter the top queue. Each dispatch runs the head of the
+// highest non-empty queue for up to its quantum (or to completion for a
+// QueueFCFS level); a process that exhausts its quantum without finishing is
+// demoted one level. Every BoostInterval time units all processes are moved
+// back to the top queue. In addition to the standard Gantt chart and
+// schedule table, it emits one Gantt band per queue and the average
+// response time (first dispatch − arrival).
+func MLFQSchedule(w io.Writer, title string, processes []Process, cfg MLFQConfig, renderer Renderer, sink EventSink) {
+	procs := make([]*mlfqProcess, len(processes))
+	for i := range processes {
+		procs[i] = &mlfqProcess{proc: processes[i], remaining: processes[i].BurstDuration, firstDispatch: -1}
+	}
+
+	emitArrivals(sink, processes)
+
+	pending := make([]*mlfqProcess, len(procs))
+	copy(pending, procs)
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].proc.ArrivalTime < pending[j].proc.ArrivalTime
+	})
+
+	queues := make([][]*mlfqProcess, len(cfg.Queues))
+	admitArrivals := func(currTime int64) {
+		for len(pending) > 0 && pending[0].proc.ArrivalTime <= currTime {
+			queues[0] = append(queues[0], pending[0])
+			pending = pending[1:]
+		}
+	}
+
+	var currTime, lastBoost int64
+	boost := func() {
+		if cfg.BoostInterval <= 0 {
+			return
+		}
+		for currTime-lastBoost >= cfg.BoostInterval {
+			lastBoost += cfg.BoostInterval
+			for level := 1; level < len(queues); level++ {
+				for _, p := range queues[level] {
+					_ = sink.Emit(Event{Kind: EventQueueMove, PID: p.proc.ProcessID, Time: currTime, Queue: intRef(0)})
+				}
+				queues[0] = append(queues[0], queues[level]...)
+				queues[level] = nil
+			}
+		}
+	}
+
+	admitArrivals(currTime)
+
+	gantt := make([]TimeSlice, 0)
+	bands := make([][]TimeSlice, len(cfg.Queues))
+	completed := 0
+
+	for completed < len(procs) {
+		boost()
+
+		level := -1
+		for i := range queues {
+			if len(queues[i]) > 0 {
+				level = i
+				break
+			}
+		}
+		if level == -1 {
+			currTime = pending[0].proc.ArrivalTime
+			admitArrivals(currTime)
+			continue
+		}
+
+		p := queues[level][0]
+		queues[level] = queues[level][1:]
+
+		if p.firstDispatch == -1 {
+			p.firstDispatch = currTime
+		}
+
+		run := p.remaining
+		usedFullQuantum := false
+		if cfg.Queues[level].Discipline == QueueRR && run >= cfg.Queues[level].Quantum {
+			run = cfg.Queues[level].Quantum
+			usedFullQuantum = true
+		}
+
+		start := currTime
+		currTime += run
+		p.remaining -= run
+
+		gantt = append(gantt, TimeSlice{PID: p.proc.ProcessID, Start: start, Stop: currTime})
+		bands[level] = append(bands[level], TimeSlice{PID: p.proc.ProcessID, Start: start, Stop: currTime})
+		_ = sink.Emit(Event{Kind: EventDispatch, PID: p.proc.ProcessID, Time: start, Queue: intRef(level)})
+
+		admitArrivals(currTime)
+
+		if p.remaining == 0 {
+			p.finish = currTime
+			completed++
+			_ = sink.Emit(Event{Kind: EventComplete, PID: p.proc.ProcessID, Time: currTime})
+			continue
+		}
+
+		_ = sink.Emit(Event{Kind: EventPreempt, PID: p.proc.ProcessID, Time: currTime, Queue: intRef(level)})
+
+		demoted := level
+		if usedFullQuantum && level+1 < len(queues) {
+			demoted = level + 1
+		}
+		if demoted != level {
+			_ = sink.Emit(Event{Kind: EventQueueMove, PID: p.proc.ProcessID, Time: currTime, Queue: intRef(demoted)})
+		}
+		queues[demoted] = append(queues[demoted], p)
+	}
+
+	schedule := make([][]string, len(procs))
+	var totalWait, totalTurnaround, totalResponse float64
+	for i, p := range procs {
+		turnaround := p.finish - p.proc.ArrivalTime
+		wait := turnaround - p.proc.BurstDuration
+		totalWait += float64(wait)
+		totalTurnaround += float64(turnaround)
+		totalResponse += float64(p.firstDispatch - p.proc.ArrivalTime)
+
+		schedule[i] = []string{
+			fmt.Sprint(p.proc.ProcessID),
+			fmt.Sprint(p.proc.Priority),
+			fmt.Sprint(p.proc.BurstDuration),
+			fmt.Sprint(p.proc.ArrivalTime),
+			fmt.Sprint(wait),
+			fmt.Sprint(turnaround),
+			fmt.Sprint(p.finish),
+		}
+	}
+
+	count := float64(len(procs))
+	aveResponse := totalResponse / count
+
+	_ = renderer.Render(w, ScheduleResult{
+		Title:             title,
+		Gantt:             gantt,
+		QueueBands:        bands,
+		Rows:              schedule,
+		AverageWait:       totalWait / count,
+		AverageTurnaround: totalTurnaround / count,
+		Throughput:        count / float64(currTime),
+		ContextSwitches:   contextSwitches(gantt),
+		AverageResponse:   &aveResponse,
+	})
+}
+
+func priorityPenalty(p Process) int64 {
+	return (p.Priority - 1) * 5
+}
+
+func min(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func outputTitle(w io.Writer, title string) {
+	_, _ = fmt.Fprintln(w, strings.Repeat("-", len(title)*2))
+	_, _ = fmt.Fprintln(w, strings.Repeat(" ", len(title)/2), title)
+	_, _ = fmt.Fprintln(w, strings.Repeat("-", len(title)*2))
+}
+
+// mergeTimeSlices collapses consecutive TimeSlices for the same PID into one,
+// so a tick-by-tick preemptive trace displays the same way a non-preemptive
+// one would.
+func mergeTimeSlices(gantt []TimeSlice) []TimeSlice {
+	if len(gantt) == 0 {
+		return gantt
+	}
+
+	merged := make([]TimeSlice, 0, len(gantt))
+	merged = append(merged, gantt[0])
+	for _, ts := range gantt[1:] {
+		last := &merged[len(merged)-1]
+		if ts.PID == last.PID && ts.Start == last.Stop {
+			last.Stop = ts.Stop
+		} else {
+			merged = append(merged, ts)
+		}
+	}
+	return merged
+}
+
+// contextSwitches counts the process switches in a (possibly tick-by-tick)
+// Gantt trace, i.e. one less than the number of slices once adjacent
+// same-PID slices are merged.
+func contextSwitches(gantt []TimeSlice) int {
+	merged := mergeTimeSlices(gantt)
+	if len(merged) == 0 {
+		return 0
+	}
+	return len(merged) - 1
+}
+
+func outputGantt(w io.Writer, gantt []TimeSlice) {
+	gantt = mergeTimeSlices(gantt)
+
+	_, _ = fmt.Fprintln(w, "Gantt schedule")
+	_, _ = fmt.Fprint(w, "|")
+	for i := range gantt {
+		pid := fmt.Sprint(gantt[i].PID)
+		padding := strings.Repeat(" ", (8-len(pid))/2)
+		_, _ = fmt.Fprint(w, padding, pid, padding, "|")
+	}
+	_, _ = fmt.Fprintln(w)
+	for i := range gantt {
+		_, _ = fmt.Fprint(w, fmt.Sprint(gantt[i].Start), "\t")
+		if len(gantt)-1 == i {
+			_, _ = fmt.Fprint(w, fmt.Sprint(gantt[i].Stop))
+		}
+	}
+	_, _ = fmt.Fprintf(w, "\n\n")
+}
+
+func outputSchedule(w io.Writer, rows [][]string, wait, turnaround, throughput float64, switches int) {
+	_, _ = fmt.Fprintln(w, "Schedule table")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"ID", "Priority", "Burst", "Arrival", "Wait", "Turnaround", "Exit"})
+	table.AppendBulk(rows)
+	table.SetFooter([]string{"", "", "", "",
+		fmt.Sprintf("Average\n%.2f", wait),
+		fmt.Sprintf("Average\n%.2f", turnaround),
+		fmt.Sprintf("Throughput\n%.2f/t", throughput)})
+	table.Render()
+	_, _ = fmt.Fprintf(w, "Context switches: %d\n\n", switches)
+}
+
+//endregion
+
+//region Loading processes.
+
+var ErrInvalidArgs = errors.New("invalid args")
+
+// LoadProcesses parses a CSV of PID,Burst,Arrival[,Priority] rows into
+// Process records.
+func LoadProcesses(r io.Reader) ([]Process, error) {
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: reading CSV", err)
+	}
+
+	processes := make([]Process, len(rows))
+	for i := range rows {
+		processes[i].ProcessID = mustStrToInt(rows[i][0])
+		processes[i].BurstDuration = mustStrToInt(rows[i][1])
+		processes[i].ArrivalTime = mustStrToInt(rows[i][2])
+		if len(rows[i]) == 4 {
+			processes[i].Priority = mustStrToInt(rows[i][3])
+		}
+	}
+
+	return processes, nil
+}
+
+func mustStrToInt(s string) int64 {
+	i, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		panic(err)
+	}
+
+	return i
+}
+
+// SystemProcessFilter configures LoadProcessesFromSystem: which OS
+// processes to sample, how long to wait between the two CPU-time samples
+// used to compute each process's recent usage, and how many of the
+// busiest processes to keep.
+type SystemProcessFilter struct {
+	SampleInterval time.Duration
+	Top            int
+	Predicate      func(pid int32, name string) bool
+}
+
+// LoadProcessesFromSystem samples currently running OS processes via
+// gopsutil and synthesizes Process records, as an alternative to
+// LoadProcesses, so the existing schedulers can run against a live
+// workload instead of a static CSV: PID becomes ProcessID, each process's
+// create time is normalized to a zero-based arrival (the earliest-created
+// process arrives at 0), the CPU time accumulated between two samples
+// taken SampleInterval apart becomes BurstDuration in milliseconds, and
+// the OS nice value is shifted into a non-negative Priority (lower nice
+// runs first, matching this package's existing Priority convention).
+func LoadProcessesFromSystem(ctx context.Context, filter SystemProcessFilter) ([]Process, error) {
+	procs, err := process.ProcessesWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: listing system processes", err)
+	}
+
+	type sample struct {
+		proc       *process.Process
+		createTime int64
+		cpuBefore  float64
+	}
+
+	samples := make([]sample, 0, len(procs))
+	for _, p := range procs {
+		name, err := p.NameWithContext(ctx)
+		if err != nil {
+			continue
+		}
+		if filter.Predicate != nil && !filter.Predicate(p.Pid, name) {
+			continue
+		}
+		createTime, err := p.CreateTimeWithContext(ctx)
+		if err != nil {
+			continue
+		}
+		times, err := p.TimesWithContext(ctx)
+		if err != nil {
+			continue
+		}
+		samples = append(samples, sample{proc: p, createTime: createTime, cpuBefore: times.User + times.System})
+	}
+
+	interval := filter.SampleInterval
+	if interval <= 0 {
+		interval = 500 * time.Millisecond
+	}
+	select {
+	case <-time.After(interval):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	type usage struct {
+		pid      int32
+		nice     int32
+		created  int64
+		cpuDelta float64
+	}
+
+	usages := make([]usage, 0, len(samples))
+	for _, s := range samples {
+		times, err := s.proc.TimesWithContext(ctx)
+		if err != nil {
+			continue
+		}
+		nice, err := s.proc.NiceWithContext(ctx)
+		if err != nil {
+			nice = 0
+		}
+
+		delta := (times.User + times.System) - s.cpuBefore
+		if delta < 0 {
+			delta = 0
+		}
+		usages = append(usages, usage{pid: s.proc.Pid, nice: nice, created: s.createTime, cpuDelta: delta})
+	}
+
+	sort.Slice(usages, func(i, j int) bool { return usages[i].cpuDelta > usages[j].cpuDelta })
+	if filter.Top > 0 && len(usages) > filter.Top {
+		usages = usages[:filter.Top]
+	}
+
+	earliestCreate := int64(-1)
+	for _, u := range usages {
+		if earliestCreate == -1 || u.created < earliestCreate {
+			earliestCreate = u.created
+		}
+	}
+
+	processes := make([]Process, len(usages))
+	for i, u := range usages {
+		burst := int64(u.cpuDelta * 1000)
+		if burst < 1 {
+			burst = 1
+		}
+		processes[i] = Process{
+			ProcessID:     int64(u.pid),
+			ArrivalTime:   (u.created - earliestCreate) / 1000,
+			BurstDuration: burst,
+			Priority:      int64(u.nice) + 20,
+		}
+	}
+
+	return processes, nil
+}
+
+//endregion
@@ -2,11 +2,12 @@ package builtins
 
 import (
 	"fmt"
+	"io"
 	"strings"
 )
 
-func Echo(args ...string) error {
+func Echo(stdout io.Writer, args ...string) error {
 	message := strings.Join(args, " ")
-	fmt.Println(message)
-	return nil
+	_, err := fmt.Fprintln(stdout, message)
+	return err
 }
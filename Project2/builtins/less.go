@@ -3,32 +3,48 @@ package builtins
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
+
+	"golang.org/x/term"
 )
 
-func Less(filename string) error {
+// pageSize returns how many lines to show before pausing, honoring the
+// attached terminal's height and falling back to a reasonable default when
+// stdout isn't a terminal (e.g. piped output).
+func pageSize() int {
+	if _, height, err := term.GetSize(int(os.Stdout.Fd())); err == nil && height > 1 {
+		return height - 1
+	}
+	return 24
+}
+
+func Less(stdin io.Reader, stdout io.Writer, filename string) error {
 	file, err := os.Open(filename)
 	if err != nil {
-		fmt.Println("Error:", err)
-		return nil
+		return fmt.Errorf("%w: opening %s", err, filename)
 	}
 	defer file.Close()
 
+	page := pageSize()
+	in := bufio.NewReader(stdin)
 	scanner := bufio.NewScanner(file)
 	lineNumber := 1
 
 	for scanner.Scan() {
-		fmt.Printf("%4d  %s\n", lineNumber, scanner.Text())
-		lineNumber++
+		fmt.Fprintf(stdout, "%4d  %s\n", lineNumber, scanner.Text())
 
-		if lineNumber%20 == 0 {
-			fmt.Print("Press Enter to continue...")
-			bufio.NewReader(os.Stdin).ReadBytes('\n')
+		if lineNumber%page == 0 {
+			fmt.Fprint(stdout, "Press Enter to continue...")
+			if _, err := in.ReadBytes('\n'); err != nil {
+				return fmt.Errorf("%w: reading pager prompt", err)
+			}
 		}
+		lineNumber++
 	}
 
 	if err := scanner.Err(); err != nil {
-		fmt.Println("Error reading file:", err)
+		return fmt.Errorf("%w: reading %s", err, filename)
 	}
 	return nil
 }
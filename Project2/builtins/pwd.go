@@ -2,17 +2,15 @@ package builtins
 
 import (
 	"fmt"
+	"io"
 	"os"
 )
 
-func Pwd() error {
-
+func Pwd(stdout io.Writer) error {
 	currentDir, err := os.Getwd()
 	if err != nil {
-
-		fmt.Println("Error:", err)
-		return nil
+		return fmt.Errorf("%w: getting working directory", err)
 	}
-	fmt.Println(currentDir)
-	return nil
+	_, err = fmt.Fprintln(stdout, currentDir)
+	return err
 }
@@ -2,16 +2,17 @@ package builtins
 
 import (
 	"fmt"
+	"io"
 	"os"
 )
 
-func Touch(filename string) error {
+func Touch(stdout io.Writer, filename string) error {
 	file, err := os.Create(filename)
 	if err != nil {
-		fmt.Println("Error:", err)
-	} else {
-		fmt.Println("File created/updated:", filename)
+		return fmt.Errorf("%w: creating %s", err, filename)
 	}
-	file.Close()
-	return nil
+	defer file.Close()
+
+	_, err = fmt.Fprintln(stdout, "File created/updated:", filename)
+	return err
 }
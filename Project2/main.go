@@ -0,0 +1,14 @@
+package main
+
+import (
+	"os"
+
+	"github.com/Brian121301/Brn0058/Project2/shell"
+)
+
+func main() {
+	sh := shell.New()
+	if err := sh.Run(os.Stdin, os.Stdout, os.Stderr); err != nil {
+		os.Exit(1)
+	}
+}
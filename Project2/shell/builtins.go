@@ -0,0 +1,295 @@
+package shell
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Brian121301/Brn0058/Project1/scheduler"
+	"github.com/Brian121301/Brn0058/Project2/builtins"
+)
+
+// standardBuiltins returns the commands every Shell registers by default:
+// the existing builtins package ported onto the Builtin interface, plus the
+// shell's own job-control and scheduling commands.
+func standardBuiltins(s *Shell) []Builtin {
+	return []Builtin{
+		pwdBuiltin{},
+		echoBuiltin{},
+		touchBuiltin{},
+		lessBuiltin{},
+		cdBuiltin{},
+		exportBuiltin{s},
+		jobsBuiltin{s},
+		fgBuiltin{s},
+		bgBuiltin{s},
+		historyBuiltin{s},
+		scheduleBuiltin{},
+		replayBuiltin{},
+	}
+}
+
+type pwdBuiltin struct{}
+
+func (pwdBuiltin) Name() string { return "pwd" }
+func (pwdBuiltin) Run(_ []string, _ io.Reader, stdout, _ io.Writer) error {
+	return builtins.Pwd(stdout)
+}
+
+type echoBuiltin struct{}
+
+func (echoBuiltin) Name() string { return "echo" }
+func (echoBuiltin) Run(args []string, _ io.Reader, stdout, _ io.Writer) error {
+	return builtins.Echo(stdout, args...)
+}
+
+type touchBuiltin struct{}
+
+func (touchBuiltin) Name() string { return "touch" }
+func (touchBuiltin) Run(args []string, _ io.Reader, stdout, _ io.Writer) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%w: usage: touch <file>", ErrSyntax)
+	}
+	return builtins.Touch(stdout, args[0])
+}
+
+type lessBuiltin struct{}
+
+func (lessBuiltin) Name() string { return "less" }
+func (lessBuiltin) Run(args []string, stdin io.Reader, stdout, _ io.Writer) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%w: usage: less <file>", ErrSyntax)
+	}
+	return builtins.Less(stdin, stdout, args[0])
+}
+
+type cdBuiltin struct{}
+
+func (cdBuiltin) Name() string { return "cd" }
+func (cdBuiltin) Run(args []string, _ io.Reader, _, _ io.Writer) error {
+	dir := os.Getenv("HOME")
+	if len(args) > 0 {
+		dir = args[0]
+	}
+	if err := os.Chdir(dir); err != nil {
+		return fmt.Errorf("%w: changing directory to %s", err, dir)
+	}
+	return nil
+}
+
+// exportBuiltin sets NAME=value overrides applied to external commands run
+// by this Shell.
+type exportBuiltin struct{ shell *Shell }
+
+func (exportBuiltin) Name() string { return "export" }
+func (b exportBuiltin) Run(args []string, _ io.Reader, _, _ io.Writer) error {
+	for _, arg := range args {
+		name, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			return fmt.Errorf("%w: usage: export NAME=value", ErrSyntax)
+		}
+		b.shell.env[name] = value
+	}
+	return nil
+}
+
+// jobsBuiltin lists background jobs and whether they're still running.
+type jobsBuiltin struct{ shell *Shell }
+
+func (jobsBuiltin) Name() string { return "jobs" }
+func (b jobsBuiltin) Run(_ []string, _ io.Reader, stdout, _ io.Writer) error {
+	for _, job := range b.shell.jobs {
+		status := "done"
+		if job.Running() {
+			status = "running"
+		}
+		fmt.Fprintf(stdout, "[%d] %s %s\n", job.ID, status, job.Command)
+	}
+	return nil
+}
+
+// fgBuiltin blocks until the named background job finishes.
+type fgBuiltin struct{ shell *Shell }
+
+func (fgBuiltin) Name() string { return "fg" }
+func (b fgBuiltin) Run(args []string, _ io.Reader, stdout, _ io.Writer) error {
+	job, err := b.shell.findJob(args)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "[%d] waiting on %s\n", job.ID, job.Command)
+	return job.Wait()
+}
+
+// bgBuiltin reports a background job's status without waiting on it.
+type bgBuiltin struct{ shell *Shell }
+
+func (bgBuiltin) Name() string { return "bg" }
+func (b bgBuiltin) Run(args []string, _ io.Reader, stdout, _ io.Writer) error {
+	job, err := b.shell.findJob(args)
+	if err != nil {
+		return err
+	}
+	status := "done"
+	if job.Running() {
+		status = "running"
+	}
+	fmt.Fprintf(stdout, "[%d] %s %s\n", job.ID, status, job.Command)
+	return nil
+}
+
+// findJob resolves the job ID named by args, defaulting to the most
+// recently started job.
+func (s *Shell) findJob(args []string) (*Job, error) {
+	if len(s.jobs) == 0 {
+		return nil, fmt.Errorf("%w: no background jobs", ErrSyntax)
+	}
+	if len(args) == 0 {
+		return s.jobs[len(s.jobs)-1], nil
+	}
+	id, err := strconv.Atoi(strings.TrimPrefix(args[0], "%"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid job id %q", ErrSyntax, args[0])
+	}
+	for _, job := range s.jobs {
+		if job.ID == id {
+			return job, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: no such job %d", ErrSyntax, id)
+}
+
+// historyBuiltin lists previously entered commands.
+type historyBuiltin struct{ shell *Shell }
+
+func (historyBuiltin) Name() string { return "history" }
+func (b historyBuiltin) Run(_ []string, _ io.Reader, stdout, _ io.Writer) error {
+	for i, line := range b.shell.history {
+		fmt.Fprintf(stdout, "%4d  %s\n", i+1, line)
+	}
+	return nil
+}
+
+// scheduleBuiltin runs one of Project1's FCFS, SJF, SJF-priority or
+// round-robin schedulers against a CSV file of processes, in the schedule
+// table/Gantt text format.
+type scheduleBuiltin struct{}
+
+func (scheduleBuiltin) Name() string { return "schedule" }
+func (scheduleBuiltin) Run(args []string, _ io.Reader, stdout, _ io.Writer) error {
+	if len(args) < 2 {
+		return fmt.Errorf("%w: usage: schedule <fcfs|sjf|sjf-priority|rr> <file.csv> [--quantum N] [--record file.jsonl]", ErrSyntax)
+	}
+
+	alg, path := args[0], args[1]
+	quantum := int64(2)
+	recordPath := ""
+	for i := 2; i < len(args); i++ {
+		switch args[i] {
+		case "--quantum":
+			if i+1 >= len(args) {
+				return fmt.Errorf("%w: missing value after --quantum", ErrSyntax)
+			}
+			q, err := strconv.ParseInt(args[i+1], 10, 64)
+			if err != nil {
+				return fmt.Errorf("%w: invalid --quantum %q", ErrSyntax, args[i+1])
+			}
+			quantum = q
+			i++
+		case "--record":
+			if i+1 >= len(args) {
+				return fmt.Errorf("%w: missing value after --record", ErrSyntax)
+			}
+			recordPath = args[i+1]
+			i++
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("%w: opening %s", err, path)
+	}
+	defer f.Close()
+
+	processes, err := scheduler.LoadProcesses(f)
+	if err != nil {
+		return fmt.Errorf("%w: loading %s", err, path)
+	}
+
+	var sink scheduler.EventSink = scheduler.NopSink{}
+	if recordPath != "" {
+		rf, err := os.Create(recordPath)
+		if err != nil {
+			return fmt.Errorf("%w: creating %s", err, recordPath)
+		}
+		defer rf.Close()
+		sink = scheduler.NewJSONLSink(rf)
+	}
+
+	renderer := scheduler.TextRenderer{}
+	switch alg {
+	case "fcfs":
+		scheduler.FCFSSchedule(stdout, "First-come, first-serve", processes, renderer, sink)
+	case "sjf":
+		scheduler.SJFSchedule(stdout, "Shortest-job-first", processes, renderer, sink)
+	case "sjf-priority":
+		scheduler.SJFPrioritySchedule(stdout, "Priority", processes, renderer, sink)
+	case "rr":
+		scheduler.RRSchedule(stdout, "Round-robin", processes, quantum, renderer, sink)
+	default:
+		return fmt.Errorf("%w: unknown scheduling algorithm %q", ErrSyntax, alg)
+	}
+	return renderer.Close(stdout)
+}
+
+// replayBuiltin re-renders a JSONL event log recorded by schedule --record
+// into a text or HTML report, reconstructing the Gantt trace and summary
+// metrics without re-running the scheduling algorithm.
+type replayBuiltin struct{}
+
+func (replayBuiltin) Name() string { return "replay" }
+func (replayBuiltin) Run(args []string, _ io.Reader, stdout, _ io.Writer) error {
+	if len(args) < 1 {
+		return fmt.Errorf("%w: usage: replay <file.jsonl> [--output text|html]", ErrSyntax)
+	}
+
+	path := args[0]
+	output := "text"
+	for i := 1; i < len(args); i++ {
+		if args[i] == "--output" && i+1 < len(args) {
+			output = args[i+1]
+			i++
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("%w: opening %s", err, path)
+	}
+	defer f.Close()
+
+	gantt, metrics, err := scheduler.ReplayFromLog(f)
+	if err != nil {
+		return fmt.Errorf("%w: replaying %s", err, path)
+	}
+
+	renderer, err := scheduler.NewRenderer(output)
+	if err != nil {
+		return err
+	}
+
+	if err := renderer.Render(stdout, scheduler.ScheduleResult{
+		Title:             path,
+		Gantt:             gantt,
+		AverageWait:       metrics.AverageWait,
+		AverageTurnaround: metrics.AverageTurnaround,
+		Throughput:        metrics.Throughput,
+		ContextSwitches:   metrics.ContextSwitches,
+		AverageResponse:   metrics.AverageResponse,
+	}); err != nil {
+		return err
+	}
+	return renderer.Close(stdout)
+}
@@ -0,0 +1,26 @@
+package shell
+
+// Job tracks a pipeline started in the background with a trailing "&".
+type Job struct {
+	ID      int
+	Command string
+	err     error
+	done    chan struct{}
+}
+
+// Running reports whether the job has not yet finished.
+func (j *Job) Running() bool {
+	select {
+	case <-j.done:
+		return false
+	default:
+		return true
+	}
+}
+
+// Wait blocks until the job finishes and returns the error it finished
+// with, if any.
+func (j *Job) Wait() error {
+	<-j.done
+	return j.err
+}
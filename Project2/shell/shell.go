@@ -0,0 +1,246 @@
+// Package shell implements a small interactive REPL that unifies the
+// builtins package with external commands, pipelines, redirection, and
+// background jobs.
+package shell
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Builtin is a command the shell runs in-process instead of exec'ing an
+// external program.
+type Builtin interface {
+	Name() string
+	Run(args []string, stdin io.Reader, stdout, stderr io.Writer) error
+}
+
+// Shell holds the REPL's state: registered builtins, exported environment
+// overrides, command history, and background jobs.
+type Shell struct {
+	builtins map[string]Builtin
+	env      map[string]string
+	history  []string
+	jobs     []*Job
+}
+
+// New returns a Shell with the standard builtins registered.
+func New() *Shell {
+	s := &Shell{
+		builtins: make(map[string]Builtin),
+		env:      make(map[string]string),
+	}
+	for _, b := range standardBuiltins(s) {
+		s.Register(b)
+	}
+	return s
+}
+
+// Register adds or replaces a builtin by name.
+func (s *Shell) Register(b Builtin) {
+	s.builtins[b.Name()] = b
+}
+
+// Run reads commands from stdin until EOF or "exit", writing output to
+// stdout/stderr.
+func (s *Shell) Run(stdin io.Reader, stdout, stderr io.Writer) error {
+	scanner := bufio.NewScanner(stdin)
+	for {
+		fmt.Fprint(stdout, "gsh> ")
+		if !scanner.Scan() {
+			return scanner.Err()
+		}
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if line == "exit" {
+			return nil
+		}
+
+		s.history = append(s.history, line)
+		if err := s.execLine(line, stdin, stdout, stderr); err != nil {
+			fmt.Fprintln(stderr, err)
+		}
+	}
+}
+
+// execLine parses and runs a single line: a pipeline of one or more
+// commands, optionally backgrounded with a trailing "&".
+func (s *Shell) execLine(line string, stdin io.Reader, stdout, stderr io.Writer) error {
+	background := false
+	if trimmed := strings.TrimSuffix(line, "&"); trimmed != line {
+		background = true
+		line = strings.TrimSpace(trimmed)
+	}
+
+	stages, err := parsePipeline(line)
+	if err != nil {
+		return err
+	}
+	if len(stages) == 0 {
+		return nil
+	}
+
+	run := func() error { return s.runPipeline(stages, stdin, stdout, stderr) }
+
+	if !background {
+		return run()
+	}
+
+	job := &Job{ID: len(s.jobs) + 1, Command: line, done: make(chan struct{})}
+	s.jobs = append(s.jobs, job)
+	go func() {
+		job.err = run()
+		close(job.done)
+	}()
+	fmt.Fprintf(stdout, "[%d] started\n", job.ID)
+	return nil
+}
+
+// stage is one command of a pipeline, with its own optional redirections.
+type stage struct {
+	argv    []string
+	inFile  string
+	outFile string
+}
+
+// parsePipeline splits a line on "|" and, within each segment, pulls out
+// "< file" and "> file" redirections from the argument list; the "<" or ">"
+// must be a token of its own. Tokens are whitespace-separated; quoting is
+// not supported.
+func parsePipeline(line string) ([]stage, error) {
+	segments := strings.Split(line, "|")
+	stages := make([]stage, 0, len(segments))
+
+	for _, segment := range segments {
+		fields := strings.Fields(segment)
+		var st stage
+		for i := 0; i < len(fields); i++ {
+			switch fields[i] {
+			case "<":
+				if i+1 >= len(fields) {
+					return nil, fmt.Errorf("%w: missing filename after <", ErrSyntax)
+				}
+				st.inFile = fields[i+1]
+				i++
+			case ">":
+				if i+1 >= len(fields) {
+					return nil, fmt.Errorf("%w: missing filename after >", ErrSyntax)
+				}
+				st.outFile = fields[i+1]
+				i++
+			default:
+				st.argv = append(st.argv, fields[i])
+			}
+		}
+		if len(st.argv) == 0 {
+			return nil, fmt.Errorf("%w: empty command", ErrSyntax)
+		}
+		stages = append(stages, st)
+	}
+
+	return stages, nil
+}
+
+// runPipeline wires each stage's stdout to the next stage's stdin, then
+// waits for all stages to finish.
+func (s *Shell) runPipeline(stages []stage, stdin io.Reader, stdout, stderr io.Writer) error {
+	n := len(stages)
+	readers := make([]io.Reader, n)
+	writers := make([]io.Writer, n)
+	closers := make([][]io.Closer, n)
+
+	readers[0] = stdin
+	writers[n-1] = stdout
+
+	for i := 0; i < n-1; i++ {
+		pr, pw := io.Pipe()
+		writers[i] = pw
+		readers[i+1] = pr
+		closers[i] = append(closers[i], pw)
+		closers[i+1] = append(closers[i+1], pr)
+	}
+
+	for i, st := range stages {
+		if st.inFile != "" {
+			f, err := os.Open(st.inFile)
+			if err != nil {
+				return fmt.Errorf("%w: opening %s", err, st.inFile)
+			}
+			readers[i] = f
+			closers[i] = append(closers[i], f)
+		}
+		if st.outFile != "" {
+			f, err := os.Create(st.outFile)
+			if err != nil {
+				return fmt.Errorf("%w: creating %s", err, st.outFile)
+			}
+			writers[i] = f
+			closers[i] = append(closers[i], f)
+		}
+	}
+
+	errs := make([]error, n)
+	done := make(chan struct{}, n)
+
+	for i, st := range stages {
+		i, st := i, st
+		go func() {
+			errs[i] = s.runStage(st, readers[i], writers[i], stderr)
+			// Closing our end of the pipe to the next stage signals EOF so
+			// it can finish reading even if we errored out early.
+			if pw, ok := writers[i].(*io.PipeWriter); ok {
+				pw.Close()
+			}
+			done <- struct{}{}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		<-done
+	}
+	for i := range stages {
+		for _, c := range closers[i] {
+			c.Close()
+		}
+		if err := errs[i]; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runStage executes one pipeline stage, preferring a registered builtin and
+// falling back to an external command looked up on PATH.
+func (s *Shell) runStage(st stage, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+	name, args := st.argv[0], st.argv[1:]
+
+	if b, ok := s.builtins[name]; ok {
+		return b.Run(args, stdin, stdout, stderr)
+	}
+
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.Env = s.environ()
+	return cmd.Run()
+}
+
+// environ returns os.Environ() with any `export`-set overrides applied.
+func (s *Shell) environ() []string {
+	env := os.Environ()
+	for k, v := range s.env {
+		env = append(env, k+"="+v)
+	}
+	return env
+}
+
+// ErrSyntax reports a malformed pipeline or redirection.
+var ErrSyntax = fmt.Errorf("syntax error")